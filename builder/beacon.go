@@ -0,0 +1,25 @@
+package builder
+
+import (
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+// ProposerDuty is the slimmed-down subset of a beacon-chain proposer duty
+// the builder needs to know which validator to build for.
+type ProposerDuty struct {
+	Slot           uint64
+	ValidatorIndex uint64
+	Pubkey         boostTypes.PublicKey
+}
+
+// IBeaconClient abstracts the consensus-layer operations the builder
+// depends on, whether backed by a real CL client (Lighthouse, Prysm, ...)
+// or, in dev mode, by a SimulatedBeaconClient.
+type IBeaconClient interface {
+	// SubscribeToPayloadAttributesEvents sends to payloadAttrC each time the
+	// CL emits new payload attributes for an upcoming slot.
+	SubscribeToPayloadAttributesEvents(payloadAttrC chan BuilderPayloadAttributes)
+
+	getProposerForNextSlot(requestedSlot uint64) (ProposerDuty, error)
+	publishBlock(block *boostTypes.SignedBeaconBlock) error
+}
@@ -0,0 +1,138 @@
+package builder
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+// BeaconClient is a thin REST/SSE client for a real consensus-layer node's
+// Beacon API, implementing IBeaconClient for production use.
+type BeaconClient struct {
+	endpoint string
+	client   *http.Client
+}
+
+func NewBeaconClient(endpoint string) *BeaconClient {
+	return &BeaconClient{
+		endpoint: strings.TrimRight(endpoint, "/"),
+		client:   &http.Client{},
+	}
+}
+
+// SubscribeToPayloadAttributesEvents streams the CL's payload_attributes
+// SSE topic and forwards each event to payloadAttrC.
+func (b *BeaconClient) SubscribeToPayloadAttributesEvents(payloadAttrC chan BuilderPayloadAttributes) {
+	go func() {
+		resp, err := b.client.Get(b.endpoint + "/eth/v1/events?topics=payload_attributes")
+		if err != nil {
+			log.Error("failed to subscribe to payload attributes events", "err", err)
+			return
+		}
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, "data:") {
+				continue
+			}
+
+			var event struct {
+				Data struct {
+					ProposalSlot      string                   `json:"proposal_slot"`
+					PayloadAttributes BuilderPayloadAttributes `json:"payload_attributes"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data:")), &event); err != nil {
+				log.Warn("failed to decode payload attributes event", "err", err)
+				continue
+			}
+
+			slot, err := strconv.ParseUint(event.Data.ProposalSlot, 10, 64)
+			if err != nil {
+				continue
+			}
+
+			attrs := event.Data.PayloadAttributes
+			attrs.Slot = slot
+			payloadAttrC <- attrs
+		}
+	}()
+}
+
+func (b *BeaconClient) getProposerForNextSlot(requestedSlot uint64) (ProposerDuty, error) {
+	const slotsPerEpoch = 32
+	url := fmt.Sprintf("%s/eth/v1/validator/duties/proposer/%d", b.endpoint, requestedSlot/slotsPerEpoch)
+
+	resp, err := b.client.Get(url)
+	if err != nil {
+		return ProposerDuty{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ProposerDuty{}, fmt.Errorf("beacon node returned status %d for proposer duties", resp.StatusCode)
+	}
+
+	var duties struct {
+		Data []struct {
+			Pubkey         string `json:"pubkey"`
+			ValidatorIndex string `json:"validator_index"`
+			Slot           string `json:"slot"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&duties); err != nil {
+		return ProposerDuty{}, err
+	}
+
+	for _, d := range duties.Data {
+		slot, err := strconv.ParseUint(d.Slot, 10, 64)
+		if err != nil || slot != requestedSlot {
+			continue
+		}
+
+		validatorIndex, err := strconv.ParseUint(d.ValidatorIndex, 10, 64)
+		if err != nil {
+			return ProposerDuty{}, err
+		}
+
+		pubkeyBytes, err := hexutil.Decode(d.Pubkey)
+		if err != nil {
+			return ProposerDuty{}, err
+		}
+		var pubkey boostTypes.PublicKey
+		copy(pubkey[:], pubkeyBytes)
+
+		return ProposerDuty{Slot: slot, ValidatorIndex: validatorIndex, Pubkey: pubkey}, nil
+	}
+
+	return ProposerDuty{}, fmt.Errorf("no proposer duty found for slot %d", requestedSlot)
+}
+
+func (b *BeaconClient) publishBlock(block *boostTypes.SignedBeaconBlock) error {
+	body, err := json.Marshal(block)
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.client.Post(b.endpoint+"/eth/v1/beacon/blocks", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("beacon node returned status %d publishing block", resp.StatusCode)
+	}
+	return nil
+}
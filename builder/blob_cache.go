@@ -0,0 +1,178 @@
+package builder
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	blobCacheHitMeter  = metrics.NewRegisteredMeter("builder/blobcache/hit", nil)
+	blobCacheMissMeter = metrics.NewRegisteredMeter("builder/blobcache/miss", nil)
+)
+
+// cachedBlob is a single blob alongside the KZG commitment and proof the EL
+// returns it with.
+type cachedBlob struct {
+	Blob       types.Blob
+	Commitment types.KZGCommitment
+	Proof      types.KZGProof
+}
+
+// blobCacheEntry is what order's list elements hold: the key alongside the
+// value, so an evicted list.Element can find its map entry.
+type blobCacheEntry struct {
+	key   common.Hash
+	value *cachedBlob
+}
+
+// BlobCache is an LRU of blobs keyed by their versioned hash, populated from
+// the blobs bundle of each block the miner seals and from engine_getBlobsV1
+// responses, and consulted at block assembly time so an already-seen blob is
+// not re-fetched from the EL. Blob transactions arriving in the txpool are
+// not added to it.
+// Both Put and Get count as a use: Get moves its entry to the front, so a
+// blob that keeps getting hit survives even if others were inserted later.
+type BlobCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[common.Hash]*list.Element
+	order    *list.List // front = most recently used, back = least
+}
+
+func NewBlobCache(capacity int) *BlobCache {
+	return &BlobCache{
+		capacity: capacity,
+		entries:  make(map[common.Hash]*list.Element, capacity),
+		order:    list.New(),
+	}
+}
+
+// Put inserts or refreshes the blob for versionedHash as the most recently
+// used entry, evicting the least recently used one if the cache is at
+// capacity.
+func (c *BlobCache) Put(versionedHash common.Hash, blob types.Blob, commitment types.KZGCommitment, proof types.KZGProof) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	value := &cachedBlob{Blob: blob, Commitment: commitment, Proof: proof}
+
+	if elem, ok := c.entries[versionedHash]; ok {
+		elem.Value.(*blobCacheEntry).value = value
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	if c.order.Len() >= c.capacity {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*blobCacheEntry).key)
+		}
+	}
+
+	c.entries[versionedHash] = c.order.PushFront(&blobCacheEntry{key: versionedHash, value: value})
+}
+
+// Get returns the cached blob for versionedHash, if present, and marks it
+// as the most recently used entry.
+func (c *BlobCache) Get(versionedHash common.Hash) (*cachedBlob, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[versionedHash]
+	if !ok {
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	return elem.Value.(*blobCacheEntry).value, true
+}
+
+// engineClient is the subset of the authenticated engine API the builder
+// needs in order to fetch blobs the local execution client already holds.
+type engineClient struct {
+	rpc *rpc.Client
+}
+
+// DialEngineClient connects to the execution client's authenticated engine
+// endpoint using the given JWT secret, as configured by
+// BuilderConfig.EngineEndpoint/EngineJWTSecret.
+func DialEngineClient(ctx context.Context, endpoint string, jwtSecret [32]byte) (*engineClient, error) {
+	client, err := rpc.DialOptions(ctx, endpoint, rpc.WithHTTPAuth(node.NewJWTAuth(jwtSecret)))
+	if err != nil {
+		return nil, err
+	}
+	return &engineClient{rpc: client}, nil
+}
+
+// FetchBlobs calls engine_getBlobsV1 for the given versioned hashes and
+// returns one response slot per hash, nil where the EL does not have the
+// blob.
+func (e *engineClient) FetchBlobs(ctx context.Context, versionedHashes []common.Hash) ([]*cachedBlob, error) {
+	var result []*struct {
+		Blob       types.Blob          `json:"blob"`
+		Commitment types.KZGCommitment `json:"kzg_commitment"`
+		Proof      types.KZGProof      `json:"kzg_proof"`
+	}
+
+	if err := e.rpc.CallContext(ctx, &result, "engine_getBlobsV1", versionedHashes); err != nil {
+		return nil, err
+	}
+
+	blobs := make([]*cachedBlob, len(result))
+	for i, r := range result {
+		if r == nil {
+			continue
+		}
+		blobs[i] = &cachedBlob{Blob: r.Blob, Commitment: r.Commitment, Proof: r.Proof}
+	}
+	return blobs, nil
+}
+
+// ResolveBlobs fills in any of versionedHashes missing from cache by
+// batching a single engine_getBlobsV1 call to the EL, caching what comes
+// back, and reports cache hit/miss counts via metrics.
+func ResolveBlobs(ctx context.Context, cache *BlobCache, engine *engineClient, versionedHashes []common.Hash) ([]*cachedBlob, error) {
+	blobs := make([]*cachedBlob, len(versionedHashes))
+
+	var missing []common.Hash
+	var missingIdx []int
+	for i, h := range versionedHashes {
+		if b, ok := cache.Get(h); ok {
+			blobs[i] = b
+			blobCacheHitMeter.Mark(1)
+			continue
+		}
+		missing = append(missing, h)
+		missingIdx = append(missingIdx, i)
+		blobCacheMissMeter.Mark(1)
+	}
+
+	if len(missing) == 0 || engine == nil {
+		return blobs, nil
+	}
+
+	fetched, err := engine.FetchBlobs(ctx, missing)
+	if err != nil {
+		log.Warn("engine_getBlobsV1 failed", "numRequested", len(missing), "err", err)
+		return blobs, nil
+	}
+
+	for i, b := range fetched {
+		if b == nil {
+			continue
+		}
+		blobs[missingIdx[i]] = b
+		cache.Put(missing[i], b.Blob, b.Commitment, b.Proof)
+	}
+
+	return blobs, nil
+}
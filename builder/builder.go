@@ -0,0 +1,203 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	apibellatrix "github.com/attestantio/go-builder-client/api/bellatrix"
+	apicapella "github.com/attestantio/go-builder-client/api/capella"
+	apideneb "github.com/attestantio/go-builder-client/api/deneb"
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-builder-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	blockvalidation "github.com/ethereum/go-ethereum/eth/block-validation"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/flashbotsextra"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/flashbots/go-boost-utils/bls"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+// IBuilder is the RPC-facing surface Service drives: lifecycle, plus the
+// payload-attributes callback the consensus client's payload attributes
+// event invokes for every upcoming slot.
+type IBuilder interface {
+	Start() error
+	Stop() error
+	OnPayloadAttribute(attrs *BuilderPayloadAttributes) error
+}
+
+// Builder seals a block for each slot's payload attributes, honouring any
+// active proposer constraints, and submits the result to its relay.
+type Builder struct {
+	builderSecretKey *bls.SecretKey
+	builderPublicKey boostTypes.PublicKey
+
+	ds    flashbotsextra.IDatabaseService
+	relay IRelay
+	eth   IEthereumService
+
+	builderSigningDomain boostTypes.Domain
+	dryRun               bool
+	validator            *blockvalidation.BlockValidationAPI
+
+	blobCache      *BlobCache
+	engine         *engineClient
+	submissionGate *SubmissionGate
+	constraints    *ConstraintsStore
+}
+
+func NewBuilder(sk *bls.SecretKey, ds flashbotsextra.IDatabaseService, relay IRelay, builderSigningDomain boostTypes.Domain, eth IEthereumService, dryRun bool, validator *blockvalidation.BlockValidationAPI, blobCache *BlobCache, engine *engineClient, submissionGate *SubmissionGate, constraints *ConstraintsStore) *Builder {
+	var pk boostTypes.PublicKey
+	copy(pk[:], bls.PublicKeyFromSecretKey(sk).Compress())
+
+	return &Builder{
+		builderSecretKey:     sk,
+		builderPublicKey:     pk,
+		ds:                   ds,
+		relay:                relay,
+		eth:                  eth,
+		builderSigningDomain: builderSigningDomain,
+		dryRun:               dryRun,
+		validator:            validator,
+		blobCache:            blobCache,
+		engine:               engine,
+		submissionGate:       submissionGate,
+		constraints:          constraints,
+	}
+}
+
+func (b *Builder) Start() error { return nil }
+
+func (b *Builder) Stop() error {
+	if b.submissionGate != nil {
+		b.submissionGate.Close()
+	}
+	return nil
+}
+
+// OnPayloadAttribute builds a block for attrs, reserving any transactions
+// the slot's proposer has constrained, and submits it to the relay.
+func (b *Builder) OnPayloadAttribute(attrs *BuilderPayloadAttributes) error {
+	if attrs == nil {
+		return errors.New("nil payload attributes")
+	}
+
+	var constraints []Constraint
+	if b.constraints != nil {
+		constraints = b.constraints.ConstraintsForSlot(attrs.Slot)
+	}
+
+	payload, sidecar, blockValue, err := b.eth.BuildBlock(attrs, constraints)
+	if err != nil {
+		return fmt.Errorf("failed to build block for slot %d: %w", attrs.Slot, err)
+	}
+
+	return b.submitBlock(attrs, payload, sidecar, blockValue)
+}
+
+func (b *Builder) submitBlock(attrs *BuilderPayloadAttributes, payload *types.ExecutionPayload, sidecar *types.BlobTxSidecar, blockValue *big.Int) error {
+	vd, err := b.relay.GetValidatorForSlot(attrs.Slot)
+	if err != nil {
+		return fmt.Errorf("no registered validator for slot %d: %w", attrs.Slot, err)
+	}
+
+	req, err := b.toVersionedSubmitBlockRequest(attrs, payload, sidecar, blockValue, vd)
+	if err != nil {
+		return err
+	}
+
+	if b.dryRun {
+		log.Info("dry run: skipping block submission", "slot", attrs.Slot, "value", blockValue)
+		return nil
+	}
+
+	// Gate immediately before the real network submission, so a request
+	// that never makes it to the relay (an unregistered validator, a
+	// malformed request, or dry-run mode) never consumes the slot's
+	// submission budget or overwrites its value baseline.
+	if b.submissionGate != nil && !b.submissionGate.Admit(attrs.Slot, blockValue) {
+		log.Debug("submission gate rejected block", "slot", attrs.Slot, "value", blockValue)
+		return nil
+	}
+
+	if err := b.relay.SubmitBlock(req, vd); err != nil {
+		return fmt.Errorf("failed to submit block for slot %d: %w", attrs.Slot, err)
+	}
+
+	return nil
+}
+
+// toVersionedSubmitBlockRequest builds the fork-versioned submission the
+// relay API expects, dispatching on payloadVersion(attrs).
+func (b *Builder) toVersionedSubmitBlockRequest(attrs *BuilderPayloadAttributes, payload *types.ExecutionPayload, sidecar *types.BlobTxSidecar, blockValue *big.Int, vd ValidatorData) (*spec.VersionedSubmitBlockRequest, error) {
+	version := payloadVersion(attrs)
+
+	trace := &apiv1.BidTrace{
+		Slot:                 attrs.Slot,
+		BuilderPubkey:        phase0.BLSPubKey(b.builderPublicKey),
+		ProposerPubkey:       phase0.BLSPubKey(vd.Pubkey),
+		ProposerFeeRecipient: bellatrix.ExecutionAddress(vd.FeeRecipient),
+		GasLimit:             attrs.GasLimit,
+	}
+	if err := trace.Value.FromBig(blockValue); err != nil {
+		return nil, err
+	}
+
+	txs := transactionsToBellatrix(payload)
+	req := &spec.VersionedSubmitBlockRequest{Version: version}
+
+	switch version {
+	case spec.DataVersionBellatrix:
+		req.Bellatrix = &apibellatrix.SubmitBlockRequest{
+			Message:          trace,
+			ExecutionPayload: &bellatrix.ExecutionPayload{Transactions: txs},
+		}
+	case spec.DataVersionCapella:
+		req.Capella = &apicapella.SubmitBlockRequest{
+			Message:          trace,
+			ExecutionPayload: &apicapella.ExecutionPayload{Transactions: txs},
+		}
+	case spec.DataVersionDeneb:
+		req.Deneb = &apideneb.SubmitBlockRequest{
+			Message:          trace,
+			ExecutionPayload: &apideneb.ExecutionPayload{Transactions: txs},
+			BlobsBundle:      blobsBundleToAPI(blobsBundleFromSidecar(sidecar)),
+		}
+	default:
+		return nil, unsupportedVersionError(version)
+	}
+
+	return req, nil
+}
+
+func transactionsToBellatrix(payload *types.ExecutionPayload) []bellatrix.Transaction {
+	txs := make([]bellatrix.Transaction, len(payload.Transactions))
+	for i, tx := range payload.Transactions {
+		txs[i] = tx
+	}
+	return txs
+}
+
+func blobsBundleToAPI(bundle *BlobsBundle) *deneb.BlobsBundle {
+	out := &deneb.BlobsBundle{
+		Commitments: make([]deneb.KZGCommitment, len(bundle.Commitments)),
+		Proofs:      make([]deneb.KZGProof, len(bundle.Proofs)),
+		Blobs:       make([]deneb.Blob, len(bundle.Blobs)),
+	}
+	for i, c := range bundle.Commitments {
+		copy(out.Commitments[i][:], c[:])
+	}
+	for i, p := range bundle.Proofs {
+		copy(out.Proofs[i][:], p[:])
+	}
+	for i, blob := range bundle.Blobs {
+		copy(out.Blobs[i][:], blob[:])
+	}
+	return out
+}
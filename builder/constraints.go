@@ -0,0 +1,190 @@
+package builder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/gorilla/mux"
+
+	"github.com/flashbots/go-boost-utils/bls"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+// Constraint is a single proposer-signed inclusion preference: the
+// transaction must land in the built block, optionally at the given index.
+type Constraint struct {
+	Tx    *types.Transaction `json:"tx"`
+	Index *uint64            `json:"index,omitempty"`
+}
+
+// ConstraintsMessage is the signed payload of the constraints API: the set
+// of constraints a proposer wants honoured for the block it will propose
+// at Slot.
+type ConstraintsMessage struct {
+	Pubkey      boostTypes.PublicKey `json:"pubkey"`
+	Slot        uint64               `json:"slot"`
+	Constraints []Constraint         `json:"constraints"`
+}
+
+// SignedConstraints is a ConstraintsMessage together with the proposer's
+// BLS signature over it.
+type SignedConstraints struct {
+	Message   ConstraintsMessage `json:"message"`
+	Signature hexutil.Bytes      `json:"signature"`
+}
+
+// ConstraintsStore holds the latest accepted constraints for each slot that
+// has an outstanding proposer duty. It is safe for concurrent use.
+type ConstraintsStore struct {
+	mu     sync.RWMutex
+	bySlot map[uint64][]Constraint
+}
+
+func NewConstraintsStore() *ConstraintsStore {
+	return &ConstraintsStore{
+		bySlot: make(map[uint64][]Constraint),
+	}
+}
+
+// SetConstraints verifies that signed was produced by expectedProposer and,
+// if so, replaces the constraint set for its slot.
+func (c *ConstraintsStore) SetConstraints(signed SignedConstraints, expectedProposer boostTypes.PublicKey, domain boostTypes.Domain) error {
+	if signed.Message.Pubkey != expectedProposer {
+		return errors.New("constraints signed by unexpected proposer")
+	}
+
+	root, err := boostTypes.ComputeSigningRoot(&signed.Message, domain)
+	if err != nil {
+		return fmt.Errorf("failed to compute constraints signing root: %w", err)
+	}
+
+	var sig boostTypes.Signature
+	copy(sig[:], signed.Signature)
+
+	ok, err := bls.VerifySignatureBytes(root[:], sig[:], expectedProposer[:])
+	if err != nil {
+		return fmt.Errorf("failed to verify constraints signature: %w", err)
+	}
+	if !ok {
+		return errors.New("invalid constraints signature")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.bySlot[signed.Message.Slot] = signed.Message.Constraints
+
+	return nil
+}
+
+// ConstraintsForSlot returns the active constraints for slot, or nil if the
+// proposer did not submit any.
+func (c *ConstraintsStore) ConstraintsForSlot(slot uint64) []Constraint {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.bySlot[slot]
+}
+
+// EvictPriorTo discards constraints for slots older than slot, so the store
+// does not grow unbounded as the chain advances.
+func (c *ConstraintsStore) EvictPriorTo(slot uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for s := range c.bySlot {
+		if s < slot {
+			delete(c.bySlot, s)
+		}
+	}
+}
+
+// HeaderWithProofs bundles a signed builder bid with an inclusion proof for
+// every constrained transaction in the payload the bid commits to.
+type HeaderWithProofs struct {
+	Bid    *boostTypes.GetHeaderResponse `json:"bid"`
+	Proofs []InclusionProof              `json:"proofs"`
+}
+
+// handleSubmitConstraints accepts a proposer-signed set of constraints for
+// an upcoming slot and, once validated, makes them visible to the block
+// building path and to SubmitConstraints on remote relays.
+func (r *LocalRelay) handleSubmitConstraints(w http.ResponseWriter, req *http.Request) {
+	var signed SignedConstraints
+	if err := json.NewDecoder(req.Body).Decode(&signed); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	proposer, err := r.proposerForSlot(signed.Message.Slot)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !r.isAuthorizedProposer(proposer) {
+		http.Error(w, "proposer is not authorized to submit constraints", http.StatusForbidden)
+		return
+	}
+
+	if err := r.constraintsStore.SetConstraints(signed, proposer, r.proposerSigningDomain); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if r.remoteRelay != nil {
+		if err := r.remoteRelay.SubmitConstraints(signed.Message.Slot, signed); err != nil {
+			log.Warn("failed to forward constraints to remote relay", "slot", signed.Message.Slot, "err", err)
+		}
+	}
+
+	log.Info("accepted constraints", "slot", signed.Message.Slot, "numConstraints", len(signed.Message.Constraints))
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleGetHeaderWithProofs is the Constraints API counterpart of
+// handleGetHeader: it returns the best bid for the slot together with
+// Merkle inclusion proofs for every transaction the proposer constrained,
+// falling back to an unconstrained local build if the constraints cannot
+// be satisfied.
+func (r *LocalRelay) handleGetHeaderWithProofs(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	slot, err := strconv.ParseUint(vars["slot"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid slot", http.StatusBadRequest)
+		return
+	}
+
+	constraints := r.constraintsStore.ConstraintsForSlot(slot)
+
+	bid, payload, err := r.bestBidForSlot(slot, vars["parent_hash"], vars["pubkey"], constraints)
+	if err != nil {
+		log.Warn("could not satisfy constraints, falling back to local build", "slot", slot, "err", err)
+
+		bid, payload, err = r.bestBidForSlot(slot, vars["parent_hash"], vars["pubkey"], nil)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		// The fallback payload was built without honouring constraints, so
+		// there is nothing to prove inclusion of.
+		constraints = nil
+	}
+
+	proofs, err := buildInclusionProofs(payload, constraints)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(HeaderWithProofs{Bid: bid, Proofs: proofs}); err != nil {
+		log.Error("failed to write header_with_proofs response", "err", err)
+	}
+}
@@ -0,0 +1,189 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/miner"
+)
+
+// IEthereumService abstracts the local execution backend used to seal
+// blocks for a given set of payload attributes.
+type IEthereumService interface {
+	BuildBlock(attrs *BuilderPayloadAttributes, constraints []Constraint) (*types.ExecutionPayload, *types.BlobTxSidecar, *big.Int, error)
+}
+
+// EthereumService drives block building against the node's own miner.
+type EthereumService struct {
+	backend   *eth.Ethereum
+	blobCache *BlobCache
+	engine    *engineClient
+}
+
+// NewEthereumService builds blocks against backend's miner. engine may be
+// nil, in which case blobs are served only from blobCache. blobCache may be
+// nil only for blocks without blob transactions: BuildBlock returns an error
+// for a block containing blob transactions when no blob cache is configured.
+func NewEthereumService(backend *eth.Ethereum, blobCache *BlobCache, engine *engineClient) *EthereumService {
+	return &EthereumService{backend: backend, blobCache: blobCache, engine: engine}
+}
+
+// BuildBlock asks the local miner to build a payload for attrs. If
+// constraints are active for this slot, it reorders the sealed payload's
+// transactions to honour any requested index before returning, logging
+// (rather than failing) if a constrained transaction didn't make it in at
+// all so the caller can fall back to an unconstrained bid.
+//
+// Reordering blob transactions invalidates the miner's BlobsBundle, whose
+// commitments/proofs/blobs are ordered to match the block's original
+// transaction order: once constraints move a blob tx, that bundle no
+// longer lines up with what the payload actually contains. So the sidecar
+// is always rebuilt from the blob cache (seeded with whatever the miner
+// just produced), keyed by each blob's versioned hash rather than its
+// position, which is reorder-safe.
+func (e *EthereumService) BuildBlock(attrs *BuilderPayloadAttributes, constraints []Constraint) (*types.ExecutionPayload, *types.BlobTxSidecar, *big.Int, error) {
+	args := &miner.BuildPayloadArgs{
+		Parent:       attrs.HeadHash,
+		Timestamp:    uint64(attrs.Timestamp),
+		FeeRecipient: attrs.SuggestedFeeRecipient,
+		Random:       attrs.Random,
+		Withdrawals:  attrs.Withdrawals,
+		BeaconRoot:   attrs.ParentBeaconBlockRoot,
+	}
+
+	built, err := e.backend.Miner().BuildPayload(args)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	envelope := built.ResolveFull()
+	payload := &types.ExecutionPayload{Transactions: envelope.ExecutionPayload.Transactions}
+
+	versionedHashes, err := blobHashesInOrder(payload)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read blob hashes from built payload: %w", err)
+	}
+	e.seedBlobCache(versionedHashes, envelope.BlobsBundle)
+
+	if len(constraints) > 0 {
+		reorderForConstraints(payload, constraints)
+		if err := verifyConstraintsSatisfied(payload, constraints); err != nil {
+			log.Warn("built block does not satisfy all constraints", "slot", attrs.Slot, "err", err)
+		}
+
+		versionedHashes, err = blobHashesInOrder(payload)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to read blob hashes from reordered payload: %w", err)
+		}
+	}
+
+	sidecar, err := e.resolveSidecar(versionedHashes)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return payload, sidecar, envelope.BlockValue, nil
+}
+
+// seedBlobCache caches the blobs the miner just produced for this block, in
+// the order they appear in bundle, so resolveSidecar can serve them back out
+// by versioned hash without an engine round-trip.
+func (e *EthereumService) seedBlobCache(versionedHashes []common.Hash, bundle *engine.BlobsBundleV1) {
+	if e.blobCache == nil || bundle == nil {
+		return
+	}
+	for i, h := range versionedHashes {
+		if i >= len(bundle.Blobs) {
+			break
+		}
+		var blob types.Blob
+		var commitment types.KZGCommitment
+		var proof types.KZGProof
+		copy(blob[:], bundle.Blobs[i])
+		copy(commitment[:], bundle.Commitments[i])
+		copy(proof[:], bundle.Proofs[i])
+		e.blobCache.Put(h, blob, commitment, proof)
+	}
+}
+
+// resolveSidecar looks up each versioned hash via ResolveBlobs, falling
+// back to the EL through e.engine for anything seedBlobCache did not just
+// populate, and assembles the result into the sidecar shape the relay
+// submission path expects.
+func (e *EthereumService) resolveSidecar(versionedHashes []common.Hash) (*types.BlobTxSidecar, error) {
+	if len(versionedHashes) == 0 {
+		return nil, nil
+	}
+	if e.blobCache == nil {
+		return nil, errors.New("block has blob transactions but no blob cache is configured")
+	}
+
+	blobs, err := ResolveBlobs(context.Background(), e.blobCache, e.engine, versionedHashes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve blobs: %w", err)
+	}
+
+	sidecar := &types.BlobTxSidecar{
+		Commitments: make([]types.KZGCommitment, len(blobs)),
+		Proofs:      make([]types.KZGProof, len(blobs)),
+		Blobs:       make([]types.Blob, len(blobs)),
+	}
+	for i, b := range blobs {
+		if b == nil {
+			return nil, fmt.Errorf("missing blob for versioned hash %s", versionedHashes[i])
+		}
+		sidecar.Commitments[i] = b.Commitment
+		sidecar.Proofs[i] = b.Proof
+		sidecar.Blobs[i] = b.Blob
+	}
+
+	return sidecar, nil
+}
+
+// blobHashesInOrder returns the versioned hashes of every blob transaction
+// in payload, in the order those transactions appear.
+func blobHashesInOrder(payload *types.ExecutionPayload) ([]common.Hash, error) {
+	var hashes []common.Hash
+	for _, raw := range payload.Transactions {
+		var tx types.Transaction
+		if err := tx.UnmarshalBinary(raw); err != nil {
+			return nil, err
+		}
+		if tx.Type() != types.BlobTxType {
+			continue
+		}
+		hashes = append(hashes, tx.BlobHashes()...)
+	}
+	return hashes, nil
+}
+
+// reorderForConstraints moves each constrained transaction already present
+// in payload to its requested index. This does not re-execute the block, so
+// it cannot be used once a real state root has been committed to; it is
+// only valid because LocalRelay's bid cache is the only consumer of the
+// resulting payload before submission.
+func reorderForConstraints(payload *types.ExecutionPayload, constraints []Constraint) {
+	for _, c := range constraints {
+		if c.Index == nil {
+			continue
+		}
+
+		from := indexOfTransaction(payload.Transactions, c.Tx)
+		to := int(*c.Index)
+		if from < 0 || from == to || to >= len(payload.Transactions) {
+			continue
+		}
+
+		tx := payload.Transactions[from]
+		txs := append(payload.Transactions[:from:from], payload.Transactions[from+1:]...)
+		txs = append(txs[:to:to], append([][]byte{tx}, txs[to:]...)...)
+		payload.Transactions = txs
+	}
+}
@@ -0,0 +1,418 @@
+package builder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/attestantio/go-builder-client/spec"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/gorilla/mux"
+
+	"github.com/flashbots/go-boost-utils/bls"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+// ForkData names the fork versions and genesis validators root a LocalRelay
+// computes its signing domains from.
+type ForkData struct {
+	GenesisForkVersion    string
+	BellatrixForkVersion  string
+	GenesisValidatorsRoot string
+}
+
+// bidState is the best block a LocalRelay has been submitted for a slot, in
+// both its signed-bid and raw-payload forms: the former is served from
+// handleGetHeader, the latter from handleGetPayload and the proofs builder.
+type bidState struct {
+	payload     *types.ExecutionPayload
+	blobsBundle *BlobsBundle
+	value       *big.Int
+	bid         *boostTypes.GetHeaderResponse
+}
+
+// LocalRelay is an in-process relay a Builder can submit to directly,
+// serving the standard builder API (and, if enabled, the constraints API)
+// out of its own best-bid cache rather than over the network.
+type LocalRelay struct {
+	relaySecretKey *bls.SecretKey
+	relayPublicKey boostTypes.PublicKey
+
+	beaconClient IBeaconClient
+
+	builderSigningDomain  boostTypes.Domain
+	proposerSigningDomain boostTypes.Domain
+	forkData              ForkData
+	enableValidatorChecks bool
+
+	constraintsStore         *ConstraintsStore
+	proposerConstraintsAllow map[string]struct{}
+	remoteRelay              IRelay
+
+	mu              sync.RWMutex
+	validators      map[boostTypes.PublicKey]ValidatorData
+	knownValidators map[boostTypes.PublicKey]bool
+	bestBid         map[uint64]*bidState
+
+	stopCh chan struct{}
+}
+
+func NewLocalRelay(sk *bls.SecretKey, beaconClient IBeaconClient, builderSigningDomain, proposerSigningDomain boostTypes.Domain, forkData ForkData, enableValidatorChecks bool, constraintsStore *ConstraintsStore, proposerConstraintsAllow []string) *LocalRelay {
+	allow := make(map[string]struct{}, len(proposerConstraintsAllow))
+	for _, pk := range proposerConstraintsAllow {
+		allow[strings.ToLower(pk)] = struct{}{}
+	}
+
+	var pk boostTypes.PublicKey
+	copy(pk[:], bls.PublicKeyFromSecretKey(sk).Compress())
+
+	return &LocalRelay{
+		relaySecretKey:           sk,
+		relayPublicKey:           pk,
+		beaconClient:             beaconClient,
+		builderSigningDomain:     builderSigningDomain,
+		proposerSigningDomain:    proposerSigningDomain,
+		forkData:                 forkData,
+		enableValidatorChecks:    enableValidatorChecks,
+		constraintsStore:         constraintsStore,
+		proposerConstraintsAllow: allow,
+		validators:               make(map[boostTypes.PublicKey]ValidatorData),
+		knownValidators:          make(map[boostTypes.PublicKey]bool),
+		bestBid:                  make(map[uint64]*bidState),
+		stopCh:                   make(chan struct{}),
+	}
+}
+
+// SetRemoteRelay wires relay in as the forwarding target for constraints
+// this LocalRelay receives directly, so a single constraints submission
+// reaches every relay the builder also submits blocks to. It is a no-op if
+// relay is this LocalRelay itself.
+func (r *LocalRelay) SetRemoteRelay(relay IRelay) {
+	if lr, ok := relay.(*LocalRelay); ok && lr == r {
+		return
+	}
+	r.remoteRelay = relay
+}
+
+// Start subscribes to the beacon client's payload attributes stream so the
+// relay can advance its notion of the current slot, evicting stale
+// constraints and bids as it goes.
+func (r *LocalRelay) Start() error {
+	if r.beaconClient == nil {
+		return nil
+	}
+
+	attrC := make(chan BuilderPayloadAttributes)
+	r.beaconClient.SubscribeToPayloadAttributesEvents(attrC)
+
+	go func() {
+		for {
+			select {
+			case attrs := <-attrC:
+				r.onSlot(attrs.Slot)
+			case <-r.stopCh:
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (r *LocalRelay) Stop() {
+	close(r.stopCh)
+}
+
+// onSlot discards bookkeeping for slots the chain has already moved past.
+func (r *LocalRelay) onSlot(slot uint64) {
+	if r.constraintsStore != nil {
+		r.constraintsStore.EvictPriorTo(slot)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for s := range r.bestBid {
+		if s < slot {
+			delete(r.bestBid, s)
+		}
+	}
+}
+
+// registerKnownValidator short-circuits handleRegisterValidator's signature
+// verification for pubkey: used to seed a SimulatedBeaconClient's validator
+// without it having to produce a real signed registration.
+func (r *LocalRelay) registerKnownValidator(pubkey boostTypes.PublicKey, feeRecipient common.Address, gasLimit uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.knownValidators[pubkey] = true
+	r.validators[pubkey] = ValidatorData{
+		Pubkey:       pubkey,
+		FeeRecipient: boostTypes.Address(feeRecipient),
+		GasLimit:     gasLimit,
+	}
+}
+
+func (r *LocalRelay) isKnownValidator(pubkey boostTypes.PublicKey) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.knownValidators[pubkey]
+}
+
+// proposerForSlot asks the beacon client which validator is due to propose
+// at slot.
+func (r *LocalRelay) proposerForSlot(slot uint64) (boostTypes.PublicKey, error) {
+	if r.beaconClient == nil {
+		return boostTypes.PublicKey{}, errors.New("no beacon client configured")
+	}
+	duty, err := r.beaconClient.getProposerForNextSlot(slot)
+	if err != nil {
+		return boostTypes.PublicKey{}, err
+	}
+	return duty.Pubkey, nil
+}
+
+// isAuthorizedProposer reports whether pubkey may submit constraints: every
+// proposer is authorized when no allowlist is configured.
+func (r *LocalRelay) isAuthorizedProposer(pubkey boostTypes.PublicKey) bool {
+	if len(r.proposerConstraintsAllow) == 0 {
+		return true
+	}
+	_, ok := r.proposerConstraintsAllow[strings.ToLower(hexutil.Encode(pubkey[:]))]
+	return ok
+}
+
+func (r *LocalRelay) handleIndex(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (r *LocalRelay) handleStatus(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleRegisterValidator validates and stores each proposer's validator
+// registration, skipping signature verification for validators registered
+// directly via registerKnownValidator (i.e. the SimulatedBeaconClient's).
+func (r *LocalRelay) handleRegisterValidator(w http.ResponseWriter, req *http.Request) {
+	var registrations []boostTypes.SignedValidatorRegistration
+	if err := json.NewDecoder(req.Body).Decode(&registrations); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for _, reg := range registrations {
+		if r.enableValidatorChecks && !r.isKnownValidator(reg.Message.Pubkey) {
+			root, err := boostTypes.ComputeSigningRoot(&reg.Message, r.builderSigningDomain)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			ok, err := bls.VerifySignatureBytes(root[:], reg.Signature[:], reg.Message.Pubkey[:])
+			if err != nil || !ok {
+				http.Error(w, "invalid validator registration signature", http.StatusBadRequest)
+				return
+			}
+		}
+
+		r.mu.Lock()
+		r.validators[reg.Message.Pubkey] = ValidatorData{
+			Pubkey:       reg.Message.Pubkey,
+			FeeRecipient: reg.Message.FeeRecipient,
+			GasLimit:     reg.Message.GasLimit,
+		}
+		r.mu.Unlock()
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// SubmitBlock implements IRelay: it signs msg's header as a bid and keeps
+// it if it improves on the best bid already known for its slot.
+func (r *LocalRelay) SubmitBlock(msg *spec.VersionedSubmitBlockRequest, _ ValidatorData) error {
+	payload, blockHash, parentHash, err := executionPayloadFromVersioned(msg)
+	if err != nil {
+		return err
+	}
+
+	value, err := blockValueFromVersioned(msg)
+	if err != nil {
+		return err
+	}
+
+	slot, err := slotFromVersioned(msg)
+	if err != nil {
+		return err
+	}
+
+	blobsBundle, err := blobsBundleFromVersioned(msg)
+	if err != nil {
+		return err
+	}
+
+	bid, err := r.signBid(blockHash, parentHash, value, msg.Version)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing := r.bestBid[slot]; existing != nil && existing.value.Cmp(value) >= 0 {
+		return nil // not an improvement over the bid we already have
+	}
+
+	r.bestBid[slot] = &bidState{payload: payload, blobsBundle: blobsBundle, value: value, bid: bid}
+	return nil
+}
+
+func (r *LocalRelay) signBid(blockHash, parentHash common.Hash, value *big.Int, version spec.DataVersion) (*boostTypes.GetHeaderResponse, error) {
+	header := &boostTypes.ExecutionPayloadHeader{
+		ParentHash: boostTypes.Hash(parentHash),
+		BlockHash:  boostTypes.Hash(blockHash),
+	}
+
+	var valueU256 boostTypes.U256Str
+	if err := valueU256.FromBig(value); err != nil {
+		return nil, err
+	}
+
+	bidMsg := &boostTypes.BuilderBid{
+		Header: header,
+		Value:  valueU256,
+		Pubkey: r.relayPublicKey,
+	}
+
+	sig, err := boostTypes.SignMessage(bidMsg, r.builderSigningDomain, r.relaySecretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &boostTypes.GetHeaderResponse{
+		Version: version.String(),
+		Data: &boostTypes.SignedBuilderBid{
+			Message:   bidMsg,
+			Signature: sig,
+		},
+	}, nil
+}
+
+// GetValidatorForSlot implements IRelay: it reports the registration for
+// whichever validator the beacon client says is due to propose at nextSlot.
+func (r *LocalRelay) GetValidatorForSlot(nextSlot uint64) (ValidatorData, error) {
+	pubkey, err := r.proposerForSlot(nextSlot)
+	if err != nil {
+		return ValidatorData{}, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	vd, ok := r.validators[pubkey]
+	if !ok {
+		return ValidatorData{}, fmt.Errorf("no registration found for proposer %x at slot %d", pubkey, nextSlot)
+	}
+	return vd, nil
+}
+
+// SubmitConstraints implements IRelay: it records constraints received on
+// behalf of slot's proposer directly into the constraints store, the same
+// path handleSubmitConstraints uses.
+func (r *LocalRelay) SubmitConstraints(slot uint64, signed SignedConstraints) error {
+	if r.constraintsStore == nil {
+		return errors.New("constraints API is not enabled on this relay")
+	}
+
+	proposer, err := r.proposerForSlot(slot)
+	if err != nil {
+		return err
+	}
+	return r.constraintsStore.SetConstraints(signed, proposer, r.proposerSigningDomain)
+}
+
+// bestBidForSlot returns the signed bid and underlying payload for slot,
+// checking that every one of constraints is satisfied by that payload.
+func (r *LocalRelay) bestBidForSlot(slot uint64, _, _ string, constraints []Constraint) (*boostTypes.GetHeaderResponse, *types.ExecutionPayload, error) {
+	r.mu.RLock()
+	state, ok := r.bestBid[slot]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, nil, fmt.Errorf("no bid known for slot %d", slot)
+	}
+
+	if err := verifyConstraintsSatisfied(state.payload, constraints); err != nil {
+		return nil, nil, err
+	}
+
+	return state.bid, state.payload, nil
+}
+
+func verifyConstraintsSatisfied(payload *types.ExecutionPayload, constraints []Constraint) error {
+	for _, c := range constraints {
+		idx := indexOfTransaction(payload.Transactions, c.Tx)
+		if idx < 0 {
+			return fmt.Errorf("constrained transaction %s not present in best bid", c.Tx.Hash())
+		}
+		if c.Index != nil && uint64(idx) != *c.Index {
+			return fmt.Errorf("constrained transaction %s at index %d, wanted %d", c.Tx.Hash(), idx, *c.Index)
+		}
+	}
+	return nil
+}
+
+func (r *LocalRelay) handleGetHeader(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	slot, err := strconv.ParseUint(vars["slot"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid slot", http.StatusBadRequest)
+		return
+	}
+
+	bid, _, err := r.bestBidForSlot(slot, vars["parent_hash"], vars["pubkey"], nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(bid); err != nil {
+		log.Error("failed to write header response", "err", err)
+	}
+}
+
+func (r *LocalRelay) handleGetPayload(w http.ResponseWriter, req *http.Request) {
+	var signedBlock boostTypes.SignedBlindedBeaconBlock
+	if err := json.NewDecoder(req.Body).Decode(&signedBlock); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	slot := signedBlock.Message.Slot
+	blockHash := signedBlock.Message.Body.ExecutionPayloadHeader.BlockHash
+
+	r.mu.RLock()
+	state, ok := r.bestBid[slot]
+	r.mu.RUnlock()
+	if !ok || state.payload == nil {
+		http.Error(w, "no known payload for slot", http.StatusBadRequest)
+		return
+	}
+	if state.bid.Data.Message.Header.BlockHash != blockHash {
+		http.Error(w, "block hash mismatch", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	resp := GetPayloadResponse{ExecutionPayload: state.payload, BlobsBundle: state.blobsBundle}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Error("failed to write payload response", "err", err)
+	}
+}
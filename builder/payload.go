@@ -0,0 +1,61 @@
+package builder
+
+import (
+	"fmt"
+
+	"github.com/attestantio/go-builder-client/spec"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BlobsBundle carries the KZG commitments, proofs and blob contents for
+// every blob-carrying transaction included in a Deneb block, in the shape
+// the builder API expects alongside GetPayloadResponse.
+type BlobsBundle struct {
+	Commitments []types.KZGCommitment `json:"commitments"`
+	Proofs      []types.KZGProof      `json:"proofs"`
+	Blobs       []types.Blob          `json:"blobs"`
+}
+
+// GetPayloadResponse is what handleGetPayload returns for a blinded block:
+// the full execution payload, plus a blobs bundle whenever the slot's bid
+// is Deneb or later.
+type GetPayloadResponse struct {
+	ExecutionPayload *types.ExecutionPayload `json:"execution_payload"`
+	BlobsBundle      *BlobsBundle            `json:"blobs_bundle,omitempty"`
+}
+
+// blobsBundleFromSidecar converts the sidecar produced alongside a locally
+// built block into the wire BlobsBundle shape. Builds without blob
+// transactions (every pre-Deneb build among them) have no sidecar and yield
+// an empty bundle.
+func blobsBundleFromSidecar(sidecar *types.BlobTxSidecar) *BlobsBundle {
+	bundle := &BlobsBundle{}
+	if sidecar == nil {
+		return bundle
+	}
+
+	bundle.Commitments = append(bundle.Commitments, sidecar.Commitments...)
+	bundle.Proofs = append(bundle.Proofs, sidecar.Proofs...)
+	bundle.Blobs = append(bundle.Blobs, sidecar.Blobs...)
+
+	return bundle
+}
+
+// payloadVersion reports which fork-versioned builder-API payload shape
+// applies to a block built for the given payload attributes: Deneb once
+// ParentBeaconBlockRoot is set (post-Cancun), Capella once withdrawals are
+// present, Bellatrix otherwise.
+func payloadVersion(attrs *BuilderPayloadAttributes) spec.DataVersion {
+	switch {
+	case attrs.ParentBeaconBlockRoot != nil:
+		return spec.DataVersionDeneb
+	case attrs.Withdrawals != nil:
+		return spec.DataVersionCapella
+	default:
+		return spec.DataVersionBellatrix
+	}
+}
+
+func unsupportedVersionError(version spec.DataVersion) error {
+	return fmt.Errorf("unsupported builder payload version %s", version)
+}
@@ -0,0 +1,77 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-builder-client/spec"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestPayloadVersion(t *testing.T) {
+	root := common.HexToHash("0x01")
+
+	tests := []struct {
+		name  string
+		attrs *BuilderPayloadAttributes
+		want  spec.DataVersion
+	}{
+		{
+			name:  "bellatrix when neither withdrawals nor a beacon block root are set",
+			attrs: &BuilderPayloadAttributes{},
+			want:  spec.DataVersionBellatrix,
+		},
+		{
+			name:  "capella once withdrawals are present",
+			attrs: &BuilderPayloadAttributes{Withdrawals: types.Withdrawals{}},
+			want:  spec.DataVersionCapella,
+		},
+		{
+			name:  "deneb once a parent beacon block root is set, even without withdrawals",
+			attrs: &BuilderPayloadAttributes{ParentBeaconBlockRoot: &root},
+			want:  spec.DataVersionDeneb,
+		},
+		{
+			name:  "deneb takes priority over capella when both are set",
+			attrs: &BuilderPayloadAttributes{Withdrawals: types.Withdrawals{}, ParentBeaconBlockRoot: &root},
+			want:  spec.DataVersionDeneb,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := payloadVersion(tt.attrs); got != tt.want {
+				t.Errorf("payloadVersion() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlobsBundleFromSidecar(t *testing.T) {
+	t.Run("nil sidecar yields an empty bundle", func(t *testing.T) {
+		bundle := blobsBundleFromSidecar(nil)
+		if len(bundle.Commitments) != 0 || len(bundle.Proofs) != 0 || len(bundle.Blobs) != 0 {
+			t.Fatalf("expected an empty bundle, got %+v", bundle)
+		}
+	})
+
+	t.Run("carries over every commitment, proof and blob", func(t *testing.T) {
+		sidecar := &types.BlobTxSidecar{
+			Blobs:       []types.Blob{{}, {}},
+			Commitments: []types.KZGCommitment{{0x01}, {0x02}},
+			Proofs:      []types.KZGProof{{0x03}, {0x04}},
+		}
+
+		bundle := blobsBundleFromSidecar(sidecar)
+
+		if len(bundle.Blobs) != len(sidecar.Blobs) {
+			t.Errorf("Blobs: got %d, want %d", len(bundle.Blobs), len(sidecar.Blobs))
+		}
+		if len(bundle.Commitments) != len(sidecar.Commitments) {
+			t.Errorf("Commitments: got %d, want %d", len(bundle.Commitments), len(sidecar.Commitments))
+		}
+		if len(bundle.Proofs) != len(sidecar.Proofs) {
+			t.Errorf("Proofs: got %d, want %d", len(bundle.Proofs), len(sidecar.Proofs))
+		}
+	})
+}
@@ -0,0 +1,227 @@
+package builder
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// SSZ list-type limits from the deneb consensus-specs execution payload
+// container. Hash-tree-roots for these types are merkleized up to their
+// fixed capacity regardless of how many items are actually present, so
+// proof depth must be derived from these limits, not from a payload's
+// runtime transaction count.
+const (
+	maxTransactionsPerPayload = 1 << 20 // MAX_TRANSACTIONS_PER_PAYLOAD
+	maxBytesPerTransaction    = 1 << 30 // MAX_BYTES_PER_TRANSACTION
+
+	// transactionsFieldGIndex is the generalized index of the Transactions
+	// field within an SSZ ExecutionPayloadHeader container (deneb): field
+	// 13 of 17, padded to 32 leaves, so 32 + 13.
+	transactionsFieldGIndex = 45
+)
+
+var (
+	// transactionsListDepth is the fixed merkle depth of the Transactions
+	// list, since each element (a Transaction hash-tree-root) is one chunk.
+	transactionsListDepth = treeDepth(maxTransactionsPerPayload)
+
+	// transactionChunkDepth is the fixed merkle depth of a single
+	// Transaction's own byte-list hash-tree-root.
+	transactionChunkDepth = treeDepth((maxBytesPerTransaction + 31) / 32)
+
+	zeroHashes = precomputeZeroHashes(64)
+)
+
+// InclusionProof is a Merkle multiproof that a transaction occupies a given
+// generalized index in the SSZ hash-tree-root of a payload's transactions
+// list.
+type InclusionProof struct {
+	TransactionIndex uint64        `json:"transaction_index"`
+	GeneralizedIndex uint64        `json:"generalized_index"`
+	MerkleHashes     []common.Hash `json:"merkle_hashes"`
+}
+
+// buildInclusionProofs produces one InclusionProof per constraint, proving
+// that constraint.Tx is present in payload.Transactions at the index it was
+// found at, verifiable against the transactions root an SSZ-aware verifier
+// computes for the same payload.
+func buildInclusionProofs(payload *types.ExecutionPayload, constraints []Constraint) ([]InclusionProof, error) {
+	if len(constraints) == 0 {
+		return nil, nil
+	}
+
+	leaves := make([]common.Hash, len(payload.Transactions))
+	for i, tx := range payload.Transactions {
+		leaves[i] = transactionHashTreeRoot(tx)
+	}
+
+	var lengthMix common.Hash
+	binary.LittleEndian.PutUint64(lengthMix[:8], uint64(len(payload.Transactions)))
+
+	proofs := make([]InclusionProof, 0, len(constraints))
+	for _, c := range constraints {
+		idx := indexOfTransaction(payload.Transactions, c.Tx)
+		if idx < 0 {
+			return nil, fmt.Errorf("constrained transaction %s missing from built payload", c.Tx.Hash())
+		}
+		if c.Index != nil && uint64(idx) != *c.Index {
+			return nil, fmt.Errorf("constrained transaction %s landed at index %d, wanted %d", c.Tx.Hash(), idx, *c.Index)
+		}
+
+		// The branch through the fixed-depth chunk tree, plus one more
+		// hash for the list's own mix_in_length sibling.
+		branch := merkleProof(leaves, idx, transactionsListDepth)
+		branch = append(branch, lengthMix)
+
+		proofs = append(proofs, InclusionProof{
+			TransactionIndex: uint64(idx),
+			GeneralizedIndex: generalizedIndex(transactionsFieldGIndex, uint64(idx), transactionsListDepth),
+			MerkleHashes:     branch,
+		})
+	}
+
+	return proofs, nil
+}
+
+// transactionHashTreeRoot computes the SSZ hash-tree-root of tx as a
+// List[byte, MAX_BYTES_PER_TRANSACTION]: pack its bytes into 32-byte
+// chunks, merkleize to the type's fixed depth, and mix in the byte length.
+func transactionHashTreeRoot(tx []byte) common.Hash {
+	numChunks := (len(tx) + 31) / 32
+	chunks := make([]common.Hash, numChunks)
+	for i := 0; i < numChunks; i++ {
+		start, end := i*32, i*32+32
+		if end > len(tx) {
+			end = len(tx)
+		}
+		copy(chunks[i][:], tx[start:end])
+	}
+
+	root := merkleRoot(chunks, transactionChunkDepth)
+
+	var lengthMix common.Hash
+	binary.LittleEndian.PutUint64(lengthMix[:8], uint64(len(tx)))
+	return sha256Pair(root, lengthMix)
+}
+
+// merkleRoot merkleizes leaves up to a fixed depth, treating any position
+// beyond len(leaves) as the zero subtree of the corresponding depth.
+func merkleRoot(leaves []common.Hash, depth uint64) common.Hash {
+	if len(leaves) == 0 {
+		return zeroHashes[depth]
+	}
+
+	layer := leaves
+	for d := uint64(0); d < depth; d++ {
+		next := make([]common.Hash, (len(layer)+1)/2)
+		for i := range next {
+			right := zeroHashes[d]
+			if 2*i+1 < len(layer) {
+				right = layer[2*i+1]
+			}
+			next[i] = sha256Pair(layer[2*i], right)
+		}
+		layer = next
+	}
+	return layer[0]
+}
+
+// merkleProof returns the depth sibling hashes from leaf idx up to the
+// root of a fixed-depth tree over leaves, treating any position beyond
+// len(leaves) as the zero subtree of the corresponding depth. Cost is
+// proportional to len(leaves)+depth, not 2^depth.
+func merkleProof(leaves []common.Hash, idx int, depth uint64) []common.Hash {
+	layer := make([]common.Hash, len(leaves))
+	copy(layer, leaves)
+
+	proof := make([]common.Hash, 0, depth)
+	for d := uint64(0); d < depth; d++ {
+		sibling := idx ^ 1
+		if sibling < len(layer) {
+			proof = append(proof, layer[sibling])
+		} else {
+			proof = append(proof, zeroHashes[d])
+		}
+
+		next := make([]common.Hash, (len(layer)+1)/2)
+		for i := range next {
+			right := zeroHashes[d]
+			if 2*i+1 < len(layer) {
+				right = layer[2*i+1]
+			}
+			next[i] = sha256Pair(layer[2*i], right)
+		}
+		layer = next
+		idx /= 2
+	}
+	return proof
+}
+
+// generalizedIndex composes the generalized index of the idx-th element of
+// a fixed-depth list field, relative to the SSZ container fieldGIndex sits
+// in. The +1 in the shift accounts for the list's own root, whose two
+// children are the merkleized data subtree and the mix_in_length chunk.
+func generalizedIndex(fieldGIndex, idx, depth uint64) uint64 {
+	relative := (uint64(1) << (depth + 1)) | idx
+	return concatGeneralizedIndices(fieldGIndex, relative)
+}
+
+// concatGeneralizedIndices composes two generalized indices the way
+// consensus-specs' concat_generalized_indices does: child's bits (after
+// its leading 1) are appended to parent's.
+func concatGeneralizedIndices(parent, child uint64) uint64 {
+	childBits := bitLength(child) - 1
+	return (parent << childBits) | (child &^ (uint64(1) << childBits))
+}
+
+func bitLength(x uint64) uint64 {
+	n := uint64(0)
+	for x > 0 {
+		x >>= 1
+		n++
+	}
+	return n
+}
+
+func treeDepth(n uint64) uint64 {
+	depth := uint64(0)
+	for (uint64(1) << depth) < n {
+		depth++
+	}
+	return depth
+}
+
+func precomputeZeroHashes(n int) []common.Hash {
+	hashes := make([]common.Hash, n+1)
+	for i := 1; i <= n; i++ {
+		hashes[i] = sha256Pair(hashes[i-1], hashes[i-1])
+	}
+	return hashes
+}
+
+func sha256Pair(a, b common.Hash) common.Hash {
+	h := sha256.New()
+	h.Write(a[:])
+	h.Write(b[:])
+	var out common.Hash
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+func indexOfTransaction(txs [][]byte, want *types.Transaction) int {
+	wantHash := want.Hash()
+	for i, tx := range txs {
+		var t types.Transaction
+		if err := t.UnmarshalBinary(tx); err != nil {
+			continue
+		}
+		if t.Hash() == wantHash {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,33 @@
+package builder
+
+import (
+	"github.com/attestantio/go-builder-client/spec"
+
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+// ValidatorData is the subset of a proposer's validator registration the
+// builder needs in order to build and submit a block on their behalf.
+type ValidatorData struct {
+	Pubkey       boostTypes.PublicKey
+	FeeRecipient boostTypes.Address
+	GasLimit     uint64
+}
+
+// IRelay abstracts the operations the builder needs from a relay, whether
+// backed by an in-process LocalRelay or a RemoteRelay speaking the builder
+// API over HTTP.
+type IRelay interface {
+	// SubmitBlock submits a fork-versioned block (Bellatrix, Capella or
+	// Deneb, the latter carrying a BlobsBundle) on behalf of vd.
+	SubmitBlock(msg *spec.VersionedSubmitBlockRequest, vd ValidatorData) error
+	GetValidatorForSlot(nextSlot uint64) (ValidatorData, error)
+
+	// SubmitConstraints forwards a proposer-signed set of constraints for a
+	// slot to the relay, so it can be taken into account by other builders
+	// competing for the same slot.
+	SubmitConstraints(slot uint64, signed SignedConstraints) error
+
+	Start() error
+	Stop()
+}
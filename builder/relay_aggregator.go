@@ -0,0 +1,368 @@
+package builder
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/attestantio/go-builder-client/spec"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// RelayAggregatorConfig controls the circuit breaker and health-check
+// cadence RelayAggregator applies uniformly to every remote relay it wraps.
+type RelayAggregatorConfig struct {
+	// ErrorThreshold is how many consecutive submission errors trip a
+	// relay's circuit breaker. Defaults to 5.
+	ErrorThreshold int
+	// Cooldown is how long a tripped relay is skipped before it is given a
+	// half-open probe. Defaults to 30s.
+	Cooldown time.Duration
+	// HealthCheckInterval is how often tripped relays are actively probed,
+	// independent of submission traffic. Defaults to 10s.
+	HealthCheckInterval time.Duration
+}
+
+func (c RelayAggregatorConfig) errorThreshold() int {
+	if c.ErrorThreshold <= 0 {
+		return 5
+	}
+	return c.ErrorThreshold
+}
+
+func (c RelayAggregatorConfig) cooldown() time.Duration {
+	if c.Cooldown <= 0 {
+		return 30 * time.Second
+	}
+	return c.Cooldown
+}
+
+func (c RelayAggregatorConfig) healthCheckInterval() time.Duration {
+	if c.HealthCheckInterval <= 0 {
+		return 10 * time.Second
+	}
+	return c.HealthCheckInterval
+}
+
+// RelayEndpointConfig describes one remote relay in RelayAggregator's fan
+// out set: its priority breaks ties between conflicting
+// GetValidatorForSlot responses, its weight is the number of submission
+// attempts given to it before a failure is accepted.
+type RelayEndpointConfig struct {
+	Endpoint string
+	Weight   int
+	Priority int
+}
+
+// attempts is how many times in total SubmitBlock tries this relay before
+// giving up on a single submission; a relay with no configured weight gets
+// one attempt.
+func (c RelayEndpointConfig) attempts() int {
+	if c.Weight <= 0 {
+		return 1
+	}
+	return c.Weight
+}
+
+// relayState tracks the rolling health of a single relay so the aggregator
+// can trip a circuit breaker around one that has started failing without
+// taking down submissions to the others.
+type relayState struct {
+	mu sync.Mutex
+
+	relay             IRelay
+	cfg               RelayEndpointConfig
+	breakerCfg        RelayAggregatorConfig
+	tripped           bool
+	trippedAt         time.Time
+	consecutiveErrors int
+	lastError         string
+	submissions       int
+}
+
+func (s *relayState) recordSuccess() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.consecutiveErrors = 0
+	s.tripped = false
+	s.submissions++
+}
+
+func (s *relayState) recordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.consecutiveErrors++
+	s.lastError = err.Error()
+	if s.consecutiveErrors >= s.breakerCfg.errorThreshold() {
+		s.tripped = true
+		s.trippedAt = time.Now()
+	}
+}
+
+// available reports whether the relay should be used: either it has never
+// tripped, or its cooldown has elapsed and it gets a half-open probe.
+func (s *relayState) available() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.tripped {
+		return true
+	}
+	return time.Since(s.trippedAt) >= s.breakerCfg.cooldown()
+}
+
+// probe actively re-checks a tripped relay outside of submission traffic,
+// untripping it on success so a quiet relay isn't stuck skipped until the
+// next block happens to be built.
+func (s *relayState) probe(nextSlot uint64) {
+	s.mu.Lock()
+	tripped := s.tripped
+	s.mu.Unlock()
+	if !tripped {
+		return
+	}
+
+	if _, err := s.relay.GetValidatorForSlot(nextSlot); err != nil {
+		s.recordError(err)
+		return
+	}
+	s.recordSuccess()
+}
+
+func (s *relayState) snapshot() RelayHealth {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return RelayHealth{
+		Endpoint:    s.cfg.Endpoint,
+		Priority:    s.cfg.Priority,
+		Weight:      s.cfg.Weight,
+		Tripped:     s.tripped,
+		LastError:   s.lastError,
+		Submissions: s.submissions,
+	}
+}
+
+// RelayHealth is the diagnostic view of a single relay's state, returned by
+// the /relays endpoint.
+type RelayHealth struct {
+	Endpoint    string `json:"endpoint"`
+	Priority    int    `json:"priority"`
+	Weight      int    `json:"weight"`
+	Tripped     bool   `json:"tripped"`
+	LastError   string `json:"lastError,omitempty"`
+	Submissions int    `json:"submissions"`
+}
+
+// RelayAggregator implements IRelay by fanning submissions out to a set of
+// remote relays plus an optional local relay, aggregating their
+// GetValidatorForSlot responses and skipping any relay whose circuit
+// breaker has tripped.
+type RelayAggregator struct {
+	remotes    []*relayState
+	localRelay *LocalRelay
+
+	cfg    RelayAggregatorConfig
+	stopCh chan struct{}
+
+	// currentSlot is the most recent slot seen via GetValidatorForSlot or
+	// SubmitBlock, used to give tripped relays' half-open probes a slot a
+	// real relay can actually answer instead of a constant.
+	currentSlot atomic.Uint64
+}
+
+// NewRelayAggregator wraps configs (in descending priority order) plus an
+// optional local relay into a single IRelay, and starts a background
+// health-check loop that probes tripped relays on cfg.HealthCheckInterval.
+func NewRelayAggregator(configs []RelayEndpointConfig, localRelay *LocalRelay, cfg RelayAggregatorConfig) *RelayAggregator {
+	agg := &RelayAggregator{
+		localRelay: localRelay,
+		cfg:        cfg,
+		stopCh:     make(chan struct{}),
+	}
+	for _, c := range configs {
+		agg.remotes = append(agg.remotes, &relayState{
+			relay:      NewRemoteRelay(c.Endpoint, localRelay),
+			cfg:        c,
+			breakerCfg: cfg,
+		})
+	}
+
+	go agg.healthCheckLoop()
+
+	return agg
+}
+
+func (a *RelayAggregator) healthCheckLoop() {
+	ticker := time.NewTicker(a.cfg.healthCheckInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			nextSlot := a.currentSlot.Load()
+			for _, r := range a.remotes {
+				r.probe(nextSlot)
+			}
+		case <-a.stopCh:
+			return
+		}
+	}
+}
+
+// SubmitBlock fans msg out to every currently-healthy relay in parallel,
+// retrying each relay up to its configured weight before treating it as a
+// failure, and returns the first error, if any, once all have responded.
+func (a *RelayAggregator) SubmitBlock(msg *spec.VersionedSubmitBlockRequest, vd ValidatorData) error {
+	if slot, err := slotFromVersioned(msg); err == nil {
+		a.currentSlot.Store(slot)
+	}
+
+	var wg sync.WaitGroup
+	var firstErrMu sync.Mutex
+	var firstErr error
+
+	recordErr := func(err error) {
+		firstErrMu.Lock()
+		defer firstErrMu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	submit := func(name string, submitFn func() error, state *relayState, attempts int) {
+		defer wg.Done()
+
+		var err error
+		for i := 0; i < attempts; i++ {
+			if err = submitFn(); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			log.Warn("relay submission failed", "relay", name, "attempts", attempts, "err", err)
+			if state != nil {
+				state.recordError(err)
+			}
+			recordErr(err)
+			return
+		}
+		if state != nil {
+			state.recordSuccess()
+		}
+	}
+
+	for _, r := range a.remotes {
+		r := r
+		if !r.available() {
+			continue
+		}
+		wg.Add(1)
+		go submit(r.cfg.Endpoint, func() error { return r.relay.SubmitBlock(msg, vd) }, r, r.cfg.attempts())
+	}
+
+	if a.localRelay != nil {
+		wg.Add(1)
+		go submit("local", func() error { return a.localRelay.SubmitBlock(msg, vd) }, nil, 1)
+	}
+
+	wg.Wait()
+	return firstErr
+}
+
+// GetValidatorForSlot aggregates responses from every healthy relay,
+// preferring the response from the highest-priority relay when more than
+// one reports a registration for the requested slot, and breaking
+// remaining ties in favour of the higher-weight relay.
+func (a *RelayAggregator) GetValidatorForSlot(nextSlot uint64) (ValidatorData, error) {
+	a.currentSlot.Store(nextSlot)
+
+	var best ValidatorData
+	var bestPriority, bestWeight = -1 << 31, -1 << 31
+	var lastErr error
+
+	check := func(vd ValidatorData, err error, priority, weight int) {
+		if err != nil {
+			lastErr = err
+			return
+		}
+		if priority > bestPriority || (priority == bestPriority && weight > bestWeight) {
+			best = vd
+			bestPriority = priority
+			bestWeight = weight
+		}
+	}
+
+	if a.localRelay != nil {
+		vd, err := a.localRelay.GetValidatorForSlot(nextSlot)
+		check(vd, err, 1<<30, 0) // the local relay outranks any remote with priority below 1<<30
+	}
+
+	for _, r := range a.remotes {
+		if !r.available() {
+			continue
+		}
+		vd, err := r.relay.GetValidatorForSlot(nextSlot)
+		check(vd, err, r.cfg.Priority, r.cfg.Weight)
+	}
+
+	if bestPriority == -1<<31 {
+		return ValidatorData{}, lastErr
+	}
+	return best, nil
+}
+
+// SubmitConstraints forwards signed to every healthy remote relay; failures
+// are logged but do not fail the call, since constraints are best-effort
+// beyond the local build path.
+func (a *RelayAggregator) SubmitConstraints(slot uint64, signed SignedConstraints) error {
+	for _, r := range a.remotes {
+		if !r.available() {
+			continue
+		}
+		if err := r.relay.SubmitConstraints(slot, signed); err != nil {
+			log.Warn("failed to forward constraints to relay", "relay", r.cfg.Endpoint, "err", err)
+			r.recordError(err)
+			continue
+		}
+		r.recordSuccess()
+	}
+	return nil
+}
+
+func (a *RelayAggregator) Start() error {
+	for _, r := range a.remotes {
+		if err := r.relay.Start(); err != nil {
+			return err
+		}
+	}
+	if a.localRelay != nil {
+		return a.localRelay.Start()
+	}
+	return nil
+}
+
+func (a *RelayAggregator) Stop() {
+	close(a.stopCh)
+	for _, r := range a.remotes {
+		r.relay.Stop()
+	}
+	if a.localRelay != nil {
+		a.localRelay.Stop()
+	}
+}
+
+// handleRelays serves a diagnostic snapshot of every remote relay's health,
+// last error and submission count, for operators to check without digging
+// through logs. The local relay is not listed.
+func (a *RelayAggregator) handleRelays(w http.ResponseWriter, _ *http.Request) {
+	health := make([]RelayHealth, 0, len(a.remotes))
+	for _, r := range a.remotes {
+		health = append(health, r.snapshot())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(health); err != nil {
+		log.Error("failed to write /relays response", "err", err)
+	}
+}
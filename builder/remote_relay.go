@@ -0,0 +1,123 @@
+package builder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/attestantio/go-builder-client/spec"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// RemoteRelay implements IRelay against a single external mev-boost relay
+// speaking the standard builder API over HTTP.
+type RemoteRelay struct {
+	endpoint   string
+	client     *http.Client
+	localRelay *LocalRelay // optional, only used to satisfy NewRelayAggregator's per-endpoint construction
+}
+
+func NewRemoteRelay(endpoint string, localRelay *LocalRelay) *RemoteRelay {
+	return &RemoteRelay{
+		endpoint:   strings.TrimRight(endpoint, "/"),
+		client:     &http.Client{Timeout: 10 * time.Second},
+		localRelay: localRelay,
+	}
+}
+
+func (r *RemoteRelay) post(path string, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.client.Post(r.endpoint+path, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("relay %s returned status %d for %s", r.endpoint, resp.StatusCode, path)
+	}
+	return nil
+}
+
+// SubmitBlock submits a fork-versioned block to the relay's builder-blocks
+// endpoint.
+func (r *RemoteRelay) SubmitBlock(msg *spec.VersionedSubmitBlockRequest, _ ValidatorData) error {
+	return r.post("/relay/v1/builder/blocks", msg)
+}
+
+// GetValidatorForSlot fetches the relay's current proposer duties and
+// returns the registration matching nextSlot.
+func (r *RemoteRelay) GetValidatorForSlot(nextSlot uint64) (ValidatorData, error) {
+	resp, err := r.client.Get(r.endpoint + "/relay/v1/builder/validators")
+	if err != nil {
+		return ValidatorData{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return ValidatorData{}, fmt.Errorf("relay %s returned status %d for validator lookup", r.endpoint, resp.StatusCode)
+	}
+
+	var registrations []struct {
+		Slot  string `json:"slot"`
+		Entry struct {
+			Message struct {
+				FeeRecipient string `json:"fee_recipient"`
+				GasLimit     string `json:"gas_limit"`
+				Pubkey       string `json:"pubkey"`
+			} `json:"message"`
+		} `json:"entry"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&registrations); err != nil {
+		return ValidatorData{}, err
+	}
+
+	for _, reg := range registrations {
+		slot, err := strconv.ParseUint(reg.Slot, 10, 64)
+		if err != nil || slot != nextSlot {
+			continue
+		}
+		return validatorDataFromRegistration(reg.Entry.Message.Pubkey, reg.Entry.Message.FeeRecipient, reg.Entry.Message.GasLimit)
+	}
+
+	return ValidatorData{}, fmt.Errorf("no registered validator for slot %d on relay %s", nextSlot, r.endpoint)
+}
+
+// SubmitConstraints forwards a proposer's signed constraints to the relay's
+// constraints API, so other builders competing for the same slot can take
+// them into account.
+func (r *RemoteRelay) SubmitConstraints(_ uint64, signed SignedConstraints) error {
+	return r.post("/eth/v1/builder/constraints", signed)
+}
+
+func (r *RemoteRelay) Start() error { return nil }
+func (r *RemoteRelay) Stop()        {}
+
+func validatorDataFromRegistration(pubkeyHex, feeRecipientHex, gasLimitStr string) (ValidatorData, error) {
+	pubkeyBytes, err := hexutil.Decode(pubkeyHex)
+	if err != nil {
+		return ValidatorData{}, err
+	}
+	feeRecipientBytes, err := hexutil.Decode(feeRecipientHex)
+	if err != nil {
+		return ValidatorData{}, err
+	}
+	gasLimit, err := strconv.ParseUint(gasLimitStr, 10, 64)
+	if err != nil {
+		return ValidatorData{}, err
+	}
+
+	var vd ValidatorData
+	copy(vd.Pubkey[:], pubkeyBytes)
+	copy(vd.FeeRecipient[:], feeRecipientBytes)
+	vd.GasLimit = gasLimit
+	return vd, nil
+}
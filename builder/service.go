@@ -1,10 +1,12 @@
 package builder
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"time"
 
 	blockvalidation "github.com/ethereum/go-ethereum/eth/block-validation"
 
@@ -25,19 +27,28 @@ import (
 )
 
 const (
-	_PathStatus            = "/eth/v1/builder/status"
-	_PathRegisterValidator = "/eth/v1/builder/validators"
-	_PathGetHeader         = "/eth/v1/builder/header/{slot:[0-9]+}/{parent_hash:0x[a-fA-F0-9]+}/{pubkey:0x[a-fA-F0-9]+}"
-	_PathGetPayload        = "/eth/v1/builder/blinded_blocks"
+	_PathStatus              = "/eth/v1/builder/status"
+	_PathRegisterValidator   = "/eth/v1/builder/validators"
+	_PathGetHeader           = "/eth/v1/builder/header/{slot:[0-9]+}/{parent_hash:0x[a-fA-F0-9]+}/{pubkey:0x[a-fA-F0-9]+}"
+	_PathGetPayload          = "/eth/v1/builder/blinded_blocks"
+	_PathSubmitConstraints   = "/eth/v1/builder/constraints"
+	_PathGetHeaderWithProofs = "/eth/v1/builder/header_with_proofs/{slot:[0-9]+}/{parent_hash:0x[a-fA-F0-9]+}/{pubkey:0x[a-fA-F0-9]+}"
+	_PathRelays              = "/relays"
+
+	_BlobCacheCapacity = 2048
+
+	_SimulatedBeaconGasLimit = 30_000_000
 )
 
 type BuilderPayloadAttributes struct {
-	Timestamp             hexutil.Uint64 `json:"timestamp"`
-	Random                common.Hash    `json:"prevRandao"`
-	SuggestedFeeRecipient common.Address `json:"suggestedFeeRecipient,omitempty"`
-	Slot                  uint64         `json:"slot"`
-	HeadHash              common.Hash    `json:"blockHash"`
+	Timestamp             hexutil.Uint64    `json:"timestamp"`
+	Random                common.Hash       `json:"prevRandao"`
+	SuggestedFeeRecipient common.Address    `json:"suggestedFeeRecipient,omitempty"`
+	Slot                  uint64            `json:"slot"`
+	HeadHash              common.Hash       `json:"blockHash"`
 	GasLimit              uint64
+	Withdrawals           types.Withdrawals `json:"withdrawals,omitempty"`
+	ParentBeaconBlockRoot *common.Hash      `json:"parentBeaconBlockRoot,omitempty"`
 }
 
 type Service struct {
@@ -68,7 +79,7 @@ func (s *Service) PayloadAttributes(payloadAttributes *BuilderPayloadAttributes)
 	return s.builder.OnPayloadAttribute(payloadAttributes)
 }
 
-func getRouter(localRelay *LocalRelay) http.Handler {
+func getRouter(localRelay *LocalRelay, enableConstraintsAPI bool, relayAggregator *RelayAggregator) http.Handler {
 	router := mux.NewRouter()
 
 	// Add routes
@@ -78,17 +89,26 @@ func getRouter(localRelay *LocalRelay) http.Handler {
 	router.HandleFunc(_PathGetHeader, localRelay.handleGetHeader).Methods(http.MethodGet)
 	router.HandleFunc(_PathGetPayload, localRelay.handleGetPayload).Methods(http.MethodPost)
 
+	if enableConstraintsAPI {
+		router.HandleFunc(_PathSubmitConstraints, localRelay.handleSubmitConstraints).Methods(http.MethodPost)
+		router.HandleFunc(_PathGetHeaderWithProofs, localRelay.handleGetHeaderWithProofs).Methods(http.MethodGet)
+	}
+
+	if relayAggregator != nil {
+		router.HandleFunc(_PathRelays, relayAggregator.handleRelays).Methods(http.MethodGet)
+	}
+
 	// Add logging and return router
 	loggedRouter := httplogger.LoggingMiddleware(router)
 	return loggedRouter
 }
 
-func NewService(listenAddr string, localRelay *LocalRelay, builder *Builder) *Service {
+func NewService(listenAddr string, localRelay *LocalRelay, builder *Builder, enableConstraintsAPI bool, relayAggregator *RelayAggregator) *Service {
 	var srv *http.Server
 	if localRelay != nil {
 		srv = &http.Server{
 			Addr:    listenAddr,
-			Handler: getRouter(localRelay),
+			Handler: getRouter(localRelay, enableConstraintsAPI, relayAggregator),
 			/*
 			   ReadTimeout:
 			   ReadHeaderTimeout:
@@ -119,6 +139,46 @@ type BuilderConfig struct {
 	BeaconEndpoint        string
 	RemoteRelayEndpoint   string
 	ValidationBlocklist   string
+
+	// EnableConstraintsAPI turns on the proposer-signed inclusion
+	// preferences flow: POST .../constraints and GET .../header_with_proofs.
+	EnableConstraintsAPI     bool
+	ProposerConstraintsAllow []string // hex-encoded BLS pubkeys authorized to submit constraints
+
+	// EngineEndpoint/EngineJWTSecret point at the local execution client's
+	// authenticated engine API, used to fetch blobs it already holds via
+	// engine_getBlobsV1 instead of re-downloading them over gossip.
+	EngineEndpoint  string
+	EngineJWTSecret string
+
+	// DevMode substitutes a SimulatedBeaconClient for a real consensus
+	// client, so the builder -> local relay -> getPayload loop can be
+	// exercised against a dev-mode execution client alone. Implied when
+	// BeaconEndpoint is empty.
+	DevMode bool
+
+	// RemoteRelayEndpoints, if it has at least one entry, causes Register
+	// to wrap them (plus the local relay, if enabled) in a RelayAggregator
+	// instead of using RemoteRelayEndpoint directly. RemoteRelayWeights and
+	// RemoteRelayPriorities are parallel to RemoteRelayEndpoints; a missing
+	// entry defaults to weight/priority 0.
+	RemoteRelayEndpoints  []string
+	RemoteRelayWeights    []int
+	RemoteRelayPriorities []int
+
+	// RelayCircuitBreakerErrorThreshold/RelayCircuitBreakerCooldownSeconds/
+	// RelayHealthCheckIntervalSeconds configure the RelayAggregator's
+	// circuit breaker. Zero values fall back to RelayAggregatorConfig's
+	// defaults.
+	RelayCircuitBreakerErrorThreshold  int
+	RelayCircuitBreakerCooldownSeconds int
+	RelayHealthCheckIntervalSeconds    int
+
+	// SubmissionGateMinValueDeltaPercent/SubmissionGateMaxPerSlot configure
+	// the SubmissionGate placed between block sealing and relay submission,
+	// so marginal reorderings don't burn CPU/network on resubmission.
+	SubmissionGateMinValueDeltaPercent int64
+	SubmissionGateMaxPerSlot           int
 }
 
 func Register(stack *node.Node, backend *eth.Ethereum, cfg *BuilderConfig) error {
@@ -161,22 +221,65 @@ func Register(stack *node.Node, backend *eth.Ethereum, cfg *BuilderConfig) error
 	copy(bellatrixForkVersion[:], bellatrixForkVersionBytes[:4])
 	proposerSigningDomain := boostTypes.ComputeDomain(boostTypes.DomainTypeBeaconProposer, bellatrixForkVersion, genesisValidatorsRoot)
 
-	beaconClient := NewBeaconClient(cfg.BeaconEndpoint)
+	var beaconClient IBeaconClient
+	var simulatedBeacon *SimulatedBeaconClient
+	if cfg.DevMode || cfg.BeaconEndpoint == "" {
+		simulatedBeacon, err = NewSimulatedBeaconClient(common.Address{}, _SimulatedBeaconGasLimit)
+		if err != nil {
+			return fmt.Errorf("failed to start simulated beacon: %w", err)
+		}
+		beaconClient = simulatedBeacon
+		log.Warn("no beacon endpoint configured, running against a simulated beacon for local testing")
+	} else {
+		beaconClient = NewBeaconClient(cfg.BeaconEndpoint)
+	}
+
+	var constraintsStore *ConstraintsStore
+	if cfg.EnableConstraintsAPI {
+		constraintsStore = NewConstraintsStore()
+	}
 
 	var localRelay *LocalRelay
 	if cfg.EnableLocalRelay {
-		localRelay = NewLocalRelay(relaySk, beaconClient, builderSigningDomain, proposerSigningDomain, ForkData{cfg.GenesisForkVersion, cfg.BellatrixForkVersion, cfg.GenesisValidatorsRoot}, cfg.EnableValidatorChecks)
+		localRelay = NewLocalRelay(relaySk, beaconClient, builderSigningDomain, proposerSigningDomain, ForkData{cfg.GenesisForkVersion, cfg.BellatrixForkVersion, cfg.GenesisValidatorsRoot}, cfg.EnableValidatorChecks, constraintsStore, cfg.ProposerConstraintsAllow)
+		if simulatedBeacon != nil {
+			simulatedBeacon.RegisterLocalRelay(localRelay)
+		}
 	}
 
+	var relayAggregator *RelayAggregator
 	var relay IRelay
-	if cfg.RemoteRelayEndpoint != "" {
+	switch {
+	case len(cfg.RemoteRelayEndpoints) > 0:
+		endpointConfigs := make([]RelayEndpointConfig, len(cfg.RemoteRelayEndpoints))
+		for i, endpoint := range cfg.RemoteRelayEndpoints {
+			c := RelayEndpointConfig{Endpoint: endpoint}
+			if i < len(cfg.RemoteRelayWeights) {
+				c.Weight = cfg.RemoteRelayWeights[i]
+			}
+			if i < len(cfg.RemoteRelayPriorities) {
+				c.Priority = cfg.RemoteRelayPriorities[i]
+			}
+			endpointConfigs[i] = c
+		}
+		relayAggregator = NewRelayAggregator(endpointConfigs, localRelay, RelayAggregatorConfig{
+			ErrorThreshold:      cfg.RelayCircuitBreakerErrorThreshold,
+			Cooldown:            time.Duration(cfg.RelayCircuitBreakerCooldownSeconds) * time.Second,
+			HealthCheckInterval: time.Duration(cfg.RelayHealthCheckIntervalSeconds) * time.Second,
+		})
+		relay = relayAggregator
+	case cfg.RemoteRelayEndpoint != "":
 		relay = NewRemoteRelay(cfg.RemoteRelayEndpoint, localRelay)
-	} else if localRelay != nil {
+	case localRelay != nil:
 		relay = localRelay
-	} else {
+	default:
 		return errors.New("neither local nor remote relay specified")
 	}
 
+	if localRelay != nil {
+		localRelay.SetRemoteRelay(relay)
+	}
+
 	var validator *blockvalidation.BlockValidationAPI
 	if cfg.DryRun {
 		var accessVerifier *blockvalidation.AccessVerifier
@@ -189,6 +292,22 @@ func Register(stack *node.Node, backend *eth.Ethereum, cfg *BuilderConfig) error
 		validator = blockvalidation.NewBlockValidationAPI(backend, accessVerifier)
 	}
 
+	blobCache := NewBlobCache(_BlobCacheCapacity)
+	var engine *engineClient
+	if cfg.EngineEndpoint != "" {
+		jwtSecretBytes, err := hexutil.Decode(cfg.EngineJWTSecret)
+		if err != nil {
+			return fmt.Errorf("invalid engine JWT secret: %w", err)
+		}
+		var jwtSecret [32]byte
+		copy(jwtSecret[:], jwtSecretBytes)
+
+		engine, err = DialEngineClient(context.Background(), cfg.EngineEndpoint, jwtSecret)
+		if err != nil {
+			return fmt.Errorf("failed to dial engine endpoint: %w", err)
+		}
+	}
+
 	// TODO: move to proper flags
 	var ds flashbotsextra.IDatabaseService
 	dbDSN := os.Getenv("FLASHBOTS_POSTGRES_DSN")
@@ -211,9 +330,14 @@ func Register(stack *node.Node, backend *eth.Ethereum, cfg *BuilderConfig) error
 		go bundleFetcher.Run()
 	}
 
-	ethereumService := NewEthereumService(backend)
-	builderBackend := NewBuilder(builderSk, ds, relay, builderSigningDomain, ethereumService, cfg.DryRun, validator)
-	builderService := NewService(cfg.ListenAddr, localRelay, builderBackend)
+	ethereumService := NewEthereumService(backend, blobCache, engine)
+	submissionGate := NewSubmissionGate(SubmissionGateConfig{
+		MinValueDeltaPercent:  cfg.SubmissionGateMinValueDeltaPercent,
+		MaxSubmissionsPerSlot: cfg.SubmissionGateMaxPerSlot,
+	})
+
+	builderBackend := NewBuilder(builderSk, ds, relay, builderSigningDomain, ethereumService, cfg.DryRun, validator, blobCache, engine, submissionGate, constraintsStore)
+	builderService := NewService(cfg.ListenAddr, localRelay, builderBackend, cfg.EnableConstraintsAPI, relayAggregator)
 
 	stack.RegisterAPIs([]rpc.API{
 		{
@@ -0,0 +1,112 @@
+package builder
+
+import (
+	"crypto/rand"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/flashbots/go-boost-utils/bls"
+	boostTypes "github.com/flashbots/go-boost-utils/types"
+)
+
+const (
+	_SimulatedSlotDuration  = 12 * time.Second
+	_SimulatedSlotsPerEpoch = 32
+)
+
+// SimulatedBeaconClient drives the builder on a fixed wall-clock period
+// without a real consensus-layer node attached, so the builder -> local
+// relay -> getPayload loop can be exercised end-to-end against a dev-mode
+// execution client. It implements IBeaconClient.
+type SimulatedBeaconClient struct {
+	mu         sync.Mutex
+	sk         *bls.SecretKey
+	pubkey     boostTypes.PublicKey
+	localRelay *LocalRelay
+
+	slot  uint64
+	epoch uint64
+
+	feeRecipient common.Address
+	gasLimit     uint64
+}
+
+// NewSimulatedBeaconClient generates a fresh, single, locally-held BLS
+// validator key and prepares a simulated beacon driving slots at the
+// standard mainnet cadence.
+func NewSimulatedBeaconClient(feeRecipient common.Address, gasLimit uint64) (*SimulatedBeaconClient, error) {
+	sk, pk, err := bls.GenerateNewKeypair()
+	if err != nil {
+		return nil, err
+	}
+
+	var pubkey boostTypes.PublicKey
+	copy(pubkey[:], pk.Compress())
+
+	return &SimulatedBeaconClient{
+		sk:           sk,
+		pubkey:       pubkey,
+		feeRecipient: feeRecipient,
+		gasLimit:     gasLimit,
+	}, nil
+}
+
+// RegisterLocalRelay wires the simulated validator into localRelay so it
+// shows up as an already-registered proposer, short-circuiting
+// handleRegisterValidator's usual signature verification step.
+func (s *SimulatedBeaconClient) RegisterLocalRelay(localRelay *LocalRelay) {
+	s.localRelay = localRelay
+	localRelay.registerKnownValidator(s.pubkey, s.feeRecipient, s.gasLimit)
+}
+
+// SubscribeToPayloadAttributesEvents ticks payloadAttrC once per simulated
+// slot with a prevRandao freshly drawn from crypto/rand.
+func (s *SimulatedBeaconClient) SubscribeToPayloadAttributesEvents(payloadAttrC chan BuilderPayloadAttributes) {
+	go func() {
+		ticker := time.NewTicker(_SimulatedSlotDuration)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			s.mu.Lock()
+			s.slot++
+			if s.slot%_SimulatedSlotsPerEpoch == 0 {
+				s.epoch++
+			}
+			slot := s.slot
+			s.mu.Unlock()
+
+			var random common.Hash
+			if _, err := rand.Read(random[:]); err != nil {
+				log.Error("simulated beacon failed to generate randao", "err", err)
+				continue
+			}
+
+			payloadAttrC <- BuilderPayloadAttributes{
+				Timestamp:             hexutil.Uint64(time.Now().Unix()),
+				Random:                random,
+				SuggestedFeeRecipient: s.feeRecipient,
+				Slot:                  slot,
+				GasLimit:              s.gasLimit,
+			}
+		}
+	}()
+}
+
+func (s *SimulatedBeaconClient) getProposerForNextSlot(requestedSlot uint64) (ProposerDuty, error) {
+	return ProposerDuty{
+		Slot:           requestedSlot,
+		ValidatorIndex: 0,
+		Pubkey:         s.pubkey,
+	}, nil
+}
+
+// publishBlock is a no-op: there is no real beacon chain for the simulated
+// block to be broadcast to.
+func (s *SimulatedBeaconClient) publishBlock(block *boostTypes.SignedBeaconBlock) error {
+	log.Info("simulated beacon: skipping publishBlock, no real CL attached")
+	return nil
+}
@@ -0,0 +1,169 @@
+package builder
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// _SubmissionGatePruneInterval is how often the background goroutine
+	// checks for slots that have fallen behind the newest one Admit has
+	// seen and resets their state.
+	_SubmissionGatePruneInterval = 12 * time.Second
+	// _SubmissionGateSlotRetention is how many slots behind the newest one
+	// seen a slot's bookkeeping is kept around for, to absorb late
+	// resubmissions for the previous slot.
+	_SubmissionGateSlotRetention = 2
+)
+
+var (
+	submissionsAdmittedMeter       = metrics.NewRegisteredMeter("builder/submissiongate/admitted", nil)
+	submissionsRejectedBudgetMeter = metrics.NewRegisteredMeter("builder/submissiongate/rejected/budget", nil)
+	submissionsRejectedValueMeter  = metrics.NewRegisteredMeter("builder/submissiongate/rejected/value", nil)
+)
+
+// SubmissionGateConfig controls how aggressively SubmissionGate lets
+// resubmissions for the same slot through.
+type SubmissionGateConfig struct {
+	// MinValueDeltaPercent is the minimum relative improvement, as a
+	// percentage, a resubmission's blockValue must show over the last
+	// admitted value in order to be admitted itself. Defaults to 1.
+	MinValueDeltaPercent int64
+	// MaxSubmissionsPerSlot caps the number of blocks admitted for a
+	// single slot, regardless of value. 0 means unlimited.
+	MaxSubmissionsPerSlot int
+}
+
+func (c SubmissionGateConfig) delta() int64 {
+	if c.MinValueDeltaPercent <= 0 {
+		return 1
+	}
+	return c.MinValueDeltaPercent
+}
+
+// slotState is the per-slot bookkeeping SubmissionGate needs to decide
+// whether to admit the next submission.
+type slotState struct {
+	lastValue   *big.Int
+	submissions int
+}
+
+// SubmissionGate is a per-slot token bucket sitting between block sealing
+// and relay submission: it always admits a slot's first submission, then
+// only admits later ones once blockValue has improved on the last admitted
+// value by at least MinValueDeltaPercent, up to MaxSubmissionsPerSlot.
+type SubmissionGate struct {
+	cfg SubmissionGateConfig
+
+	mu          sync.Mutex
+	slots       map[uint64]*slotState
+	highestSlot uint64
+
+	stopCh chan struct{}
+}
+
+func NewSubmissionGate(cfg SubmissionGateConfig) *SubmissionGate {
+	g := &SubmissionGate{
+		cfg:    cfg,
+		slots:  make(map[uint64]*slotState),
+		stopCh: make(chan struct{}),
+	}
+	go g.pruneLoop()
+	return g
+}
+
+// Close stops the background prune goroutine.
+func (g *SubmissionGate) Close() {
+	close(g.stopCh)
+}
+
+// pruneLoop periodically resets bookkeeping for slots that have fallen
+// more than _SubmissionGateSlotRetention behind the newest slot Admit has
+// seen, so g.slots does not grow for the life of the process.
+func (g *SubmissionGate) pruneLoop() {
+	ticker := time.NewTicker(_SubmissionGatePruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			g.pruneStaleSlots()
+		case <-g.stopCh:
+			return
+		}
+	}
+}
+
+func (g *SubmissionGate) pruneStaleSlots() {
+	g.mu.Lock()
+	highestSlot := g.highestSlot
+	stale := make([]uint64, 0)
+	for slot := range g.slots {
+		if slot+_SubmissionGateSlotRetention < highestSlot {
+			stale = append(stale, slot)
+		}
+	}
+	g.mu.Unlock()
+
+	for _, slot := range stale {
+		g.ResetSlot(slot)
+	}
+}
+
+// Admit reports whether a block worth value should be submitted for slot.
+// If admitted, it becomes the new baseline that later submissions for the
+// same slot must beat.
+func (g *SubmissionGate) Admit(slot uint64, value *big.Int) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if slot > g.highestSlot {
+		g.highestSlot = slot
+	}
+
+	state, ok := g.slots[slot]
+	if !ok {
+		state = &slotState{}
+		g.slots[slot] = state
+	}
+
+	if g.cfg.MaxSubmissionsPerSlot > 0 && state.submissions >= g.cfg.MaxSubmissionsPerSlot {
+		submissionsRejectedBudgetMeter.Mark(1)
+		log.Debug("submission gate: rejecting, slot budget exhausted", "slot", slot, "budget", g.cfg.MaxSubmissionsPerSlot)
+		return false
+	}
+
+	if state.lastValue != nil {
+		if !exceedsByPercent(value, state.lastValue, g.cfg.delta()) {
+			submissionsRejectedValueMeter.Mark(1)
+			log.Debug("submission gate: rejecting, insufficient value improvement", "slot", slot, "value", value, "lastValue", state.lastValue)
+			return false
+		}
+	}
+
+	state.lastValue = new(big.Int).Set(value)
+	state.submissions++
+	submissionsAdmittedMeter.Mark(1)
+	return true
+}
+
+// ResetSlot drops the bookkeeping for slot; pruneStaleSlots calls it for
+// slots that have fallen behind the newest slot Admit has seen, so old
+// slots don't leak.
+func (g *SubmissionGate) ResetSlot(slot uint64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.slots, slot)
+}
+
+// exceedsByPercent reports whether value exceeds baseline by at least
+// percent percent, i.e. value >= baseline * (100 + percent) / 100.
+func exceedsByPercent(value, baseline *big.Int, percent int64) bool {
+	threshold := new(big.Int).Mul(baseline, big.NewInt(100+percent))
+	scaledValue := new(big.Int).Mul(value, big.NewInt(100))
+	return scaledValue.Cmp(threshold) >= 0
+}
@@ -0,0 +1,74 @@
+package builder
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestSubmissionGateAdmit(t *testing.T) {
+	t.Run("always admits a slot's first submission", func(t *testing.T) {
+		g := NewSubmissionGate(SubmissionGateConfig{})
+		defer g.Close()
+
+		if !g.Admit(1, big.NewInt(100)) {
+			t.Fatal("expected first submission for a slot to be admitted")
+		}
+	})
+
+	t.Run("rejects a resubmission that does not clear MinValueDeltaPercent", func(t *testing.T) {
+		g := NewSubmissionGate(SubmissionGateConfig{MinValueDeltaPercent: 10})
+		defer g.Close()
+
+		g.Admit(1, big.NewInt(100))
+
+		if g.Admit(1, big.NewInt(105)) {
+			t.Fatal("expected a 5% improvement to be rejected against a 10% threshold")
+		}
+		if !g.Admit(1, big.NewInt(110)) {
+			t.Fatal("expected a 10% improvement to be admitted")
+		}
+	})
+
+	t.Run("rejects once MaxSubmissionsPerSlot is exhausted regardless of value", func(t *testing.T) {
+		g := NewSubmissionGate(SubmissionGateConfig{MaxSubmissionsPerSlot: 1})
+		defer g.Close()
+
+		if !g.Admit(1, big.NewInt(100)) {
+			t.Fatal("expected the first submission to be admitted")
+		}
+		if g.Admit(1, big.NewInt(1000)) {
+			t.Fatal("expected a second submission to be rejected once the budget is exhausted")
+		}
+	})
+
+	t.Run("tracks each slot's budget and baseline independently", func(t *testing.T) {
+		g := NewSubmissionGate(SubmissionGateConfig{MaxSubmissionsPerSlot: 1})
+		defer g.Close()
+
+		g.Admit(1, big.NewInt(100))
+		if !g.Admit(2, big.NewInt(1)) {
+			t.Fatal("expected a different slot's first submission to be admitted regardless of slot 1's state")
+		}
+	})
+}
+
+func TestSubmissionGatePruneStaleSlots(t *testing.T) {
+	g := NewSubmissionGate(SubmissionGateConfig{MaxSubmissionsPerSlot: 1})
+	defer g.Close()
+
+	g.Admit(1, big.NewInt(100))
+	g.Admit(1+_SubmissionGateSlotRetention+1, big.NewInt(100))
+
+	g.pruneStaleSlots()
+
+	g.mu.Lock()
+	_, stillTracked := g.slots[1]
+	g.mu.Unlock()
+	if stillTracked {
+		t.Fatal("expected slot 1's bookkeeping to be pruned once it fell behind retention")
+	}
+
+	if !g.Admit(1, big.NewInt(1)) {
+		t.Fatal("expected slot 1 to behave like a fresh slot once pruned")
+	}
+}
@@ -0,0 +1,95 @@
+package builder
+
+import (
+	"math/big"
+
+	"github.com/attestantio/go-builder-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// executionPayloadFromVersioned extracts the go-ethereum-shaped
+// ExecutionPayload our SSZ inclusion-proof code and constraints checking
+// operate on, from whichever fork variant msg carries, along with its
+// block and parent hash.
+func executionPayloadFromVersioned(msg *spec.VersionedSubmitBlockRequest) (payload *types.ExecutionPayload, blockHash, parentHash common.Hash, err error) {
+	switch msg.Version {
+	case spec.DataVersionBellatrix:
+		p := msg.Bellatrix.ExecutionPayload
+		return payloadFromTransactions(p.Transactions), common.Hash(p.BlockHash), common.Hash(p.ParentHash), nil
+	case spec.DataVersionCapella:
+		p := msg.Capella.ExecutionPayload
+		return payloadFromTransactions(p.Transactions), common.Hash(p.BlockHash), common.Hash(p.ParentHash), nil
+	case spec.DataVersionDeneb:
+		p := msg.Deneb.ExecutionPayload
+		return payloadFromTransactions(p.Transactions), common.Hash(p.BlockHash), common.Hash(p.ParentHash), nil
+	default:
+		return nil, common.Hash{}, common.Hash{}, unsupportedVersionError(msg.Version)
+	}
+}
+
+func payloadFromTransactions(txs []bellatrix.Transaction) *types.ExecutionPayload {
+	raw := make([][]byte, len(txs))
+	for i, tx := range txs {
+		raw[i] = tx
+	}
+	return &types.ExecutionPayload{Transactions: raw}
+}
+
+// blockValueFromVersioned returns the bid value msg's BidTrace carries,
+// regardless of fork version.
+func blockValueFromVersioned(msg *spec.VersionedSubmitBlockRequest) (*big.Int, error) {
+	switch msg.Version {
+	case spec.DataVersionBellatrix:
+		return msg.Bellatrix.Message.Value.ToBig(), nil
+	case spec.DataVersionCapella:
+		return msg.Capella.Message.Value.ToBig(), nil
+	case spec.DataVersionDeneb:
+		return msg.Deneb.Message.Value.ToBig(), nil
+	default:
+		return nil, unsupportedVersionError(msg.Version)
+	}
+}
+
+// slotFromVersioned returns the slot msg's BidTrace commits to, regardless
+// of fork version.
+func slotFromVersioned(msg *spec.VersionedSubmitBlockRequest) (uint64, error) {
+	switch msg.Version {
+	case spec.DataVersionBellatrix:
+		return msg.Bellatrix.Message.Slot, nil
+	case spec.DataVersionCapella:
+		return msg.Capella.Message.Slot, nil
+	case spec.DataVersionDeneb:
+		return msg.Deneb.Message.Slot, nil
+	default:
+		return 0, unsupportedVersionError(msg.Version)
+	}
+}
+
+// blobsBundleFromVersioned extracts the blobs bundle msg's Deneb payload
+// carries, converted into our own BlobsBundle shape. Pre-Deneb versions
+// carry no blobs bundle, so they return nil.
+func blobsBundleFromVersioned(msg *spec.VersionedSubmitBlockRequest) (*BlobsBundle, error) {
+	if msg.Version != spec.DataVersionDeneb {
+		return nil, nil
+	}
+
+	src := msg.Deneb.BlobsBundle
+	bundle := &BlobsBundle{
+		Commitments: make([]types.KZGCommitment, len(src.Commitments)),
+		Proofs:      make([]types.KZGProof, len(src.Proofs)),
+		Blobs:       make([]types.Blob, len(src.Blobs)),
+	}
+	for i, c := range src.Commitments {
+		copy(bundle.Commitments[i][:], c[:])
+	}
+	for i, p := range src.Proofs {
+		copy(bundle.Proofs[i][:], p[:])
+	}
+	for i, b := range src.Blobs {
+		copy(bundle.Blobs[i][:], b[:])
+	}
+
+	return bundle, nil
+}